@@ -1,22 +1,22 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
-	"sync"
+	"strconv"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
-)
 
-const (
-	retryAfter       = time.Second
-	cooldownDuration = time.Second * 5
-	maxRetries       = 100
+	"cosmos-blocks/config"
+	"cosmos-blocks/storage"
 )
 
 var (
@@ -26,13 +26,107 @@ var (
 // blockResponse contains the essential data of the /block response.
 // More info: https://www.quicknode.com/docs/cosmos/block
 type blockResponse struct {
-	Result struct {
-		Block struct {
-			Data struct {
-				Txs []interface{} `json:"txs"`
-			} `json:"data"`
-		} `json:"block"`
-	} `json:"result"`
+	Result blockResult `json:"result"`
+}
+
+// blockResult is the "result" payload of a /block response, also used to
+// decode each entry of a batched JSON-RPC "block" call. Txs holds each
+// transaction's raw bytes, base64-encoded exactly as the node returns them.
+type blockResult struct {
+	Block struct {
+		Data struct {
+			Txs []string `json:"txs"`
+		} `json:"data"`
+	} `json:"block"`
+}
+
+// blockResultsResponse contains the "result" payload of a /block_results
+// response.
+// More info: https://docs.cometbft.com/main/rpc/#/Info/block_results
+type blockResultsResponse struct {
+	Result blockResultsResult `json:"result"`
+}
+
+// blockResultsResult is the "result" payload of a /block_results response,
+// also used to decode each entry of a batched JSON-RPC "block_results"
+// call.
+type blockResultsResult struct {
+	TxsResults       []txResultResponse `json:"txs_results"`
+	BeginBlockEvents []event            `json:"begin_block_events"`
+	EndBlockEvents   []event            `json:"end_block_events"`
+}
+
+// txResultResponse is a single entry of a /block_results response's
+// "txs_results" array. GasWanted and GasUsed are decoded as strings, as
+// Tendermint/CometBFT encodes them, and parsed separately.
+type txResultResponse struct {
+	Code      uint32  `json:"code"`
+	GasWanted string  `json:"gas_wanted"`
+	GasUsed   string  `json:"gas_used"`
+	Events    []event `json:"events"`
+}
+
+// txResult is a single transaction within a block, combining its raw bytes
+// (from /block) with its execution outcome (from /block_results).
+type txResult struct {
+	Tx        []byte
+	Code      uint32
+	GasWanted int64
+	GasUsed   int64
+	Events    []event
+}
+
+// event is a single typed event emitted during block or transaction
+// execution.
+type event struct {
+	Type       string           `json:"type"`
+	Attributes []eventAttribute `json:"attributes"`
+}
+
+// eventAttribute is a single key/value pair attached to an event.
+type eventAttribute struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// blockResults is the decoded counterpart of blockResultsResult, with its
+// gas figures parsed into integers. Its txResults are not yet paired with
+// their tx bytes; mergeTxResults does that once the corresponding /block
+// response is available.
+type blockResults struct {
+	TxsResults       []txResult
+	BeginBlockEvents []event
+	EndBlockEvents   []event
+}
+
+// jsonRPCRequest represents a single JSON-RPC 2.0 request, as sent within
+// a batch POSTed to the node's RPC endpoint.
+// More info: https://www.jsonrpc.org/specification#batch
+type jsonRPCRequest struct {
+	JSONRPC string      `json:"jsonrpc"`
+	ID      int         `json:"id"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params"`
+}
+
+// blockHeightParams are the params of a Tendermint "block" JSON-RPC
+// method call.
+type blockHeightParams struct {
+	Height string `json:"height"`
+}
+
+// jsonRPCError represents a JSON-RPC 2.0 error object.
+type jsonRPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// jsonRPCResponse represents a single entry of a batched JSON-RPC 2.0
+// response.
+type jsonRPCResponse struct {
+	ID     int             `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *jsonRPCError   `json:"error"`
 }
 
 // statusResponse contains the essential data of the /status response.
@@ -42,35 +136,206 @@ type statusResponse struct {
 		NodeInfo struct {
 			Network string `json:"network"`
 		} `json:"node_info"`
+		SyncInfo struct {
+			LatestBlockHeight string `json:"latest_block_height"`
+		} `json:"sync_info"`
 	} `json:"result"`
 }
 
-// block contains a single block's metadata.
+// block contains a single block's metadata. Txs, BeginBlockEvents and
+// EndBlockEvents are only populated when the client was asked to include
+// events (see --include-events).
 type block struct {
-	Height    int64  `json:"height"`
-	NumTxs    int    `json:"num_txs"`
-	NetworkID string `json:"network_id"`
+	Height           int64      `json:"height"`
+	NumTxs           int        `json:"num_txs"`
+	NetworkID        string     `json:"network_id"`
+	Txs              []txResult `json:"txs,omitempty"`
+	BeginBlockEvents []event    `json:"begin_block_events,omitempty"`
+	EndBlockEvents   []event    `json:"end_block_events,omitempty"`
+}
+
+// toStorageBlock converts a block into its storage representation.
+func (b block) toStorageBlock() storage.Block {
+	return storage.Block{
+		Height:           b.Height,
+		NumTxs:           b.NumTxs,
+		NetworkID:        b.NetworkID,
+		Txs:              toStorageTxResults(b.Txs),
+		BeginBlockEvents: toStorageEvents(b.BeginBlockEvents),
+		EndBlockEvents:   toStorageEvents(b.EndBlockEvents),
+	}
+}
+
+// blockFromStorage converts a storage.Block back into a block.
+func blockFromStorage(b storage.Block) block {
+	return block{
+		Height:           b.Height,
+		NumTxs:           b.NumTxs,
+		NetworkID:        b.NetworkID,
+		Txs:              txResultsFromStorage(b.Txs),
+		BeginBlockEvents: eventsFromStorage(b.BeginBlockEvents),
+		EndBlockEvents:   eventsFromStorage(b.EndBlockEvents),
+	}
+}
+
+// toStorageTxResults converts a slice of txResult into its storage
+// representation.
+func toStorageTxResults(txs []txResult) []storage.TxResult {
+	if txs == nil {
+		return nil
+	}
+
+	out := make([]storage.TxResult, len(txs))
+	for i, t := range txs {
+		out[i] = storage.TxResult{
+			Tx:        t.Tx,
+			Code:      t.Code,
+			GasWanted: t.GasWanted,
+			GasUsed:   t.GasUsed,
+			Events:    toStorageEvents(t.Events),
+		}
+	}
+
+	return out
+}
+
+// txResultsFromStorage converts a slice of storage.TxResult back into
+// txResults.
+func txResultsFromStorage(txs []storage.TxResult) []txResult {
+	if txs == nil {
+		return nil
+	}
+
+	out := make([]txResult, len(txs))
+	for i, t := range txs {
+		out[i] = txResult{
+			Tx:        t.Tx,
+			Code:      t.Code,
+			GasWanted: t.GasWanted,
+			GasUsed:   t.GasUsed,
+			Events:    eventsFromStorage(t.Events),
+		}
+	}
+
+	return out
+}
+
+// toStorageEvents converts a slice of event into its storage
+// representation.
+func toStorageEvents(evs []event) []storage.Event {
+	if evs == nil {
+		return nil
+	}
+
+	out := make([]storage.Event, len(evs))
+	for i, e := range evs {
+		out[i] = storage.Event{
+			Type:       e.Type,
+			Attributes: toStorageEventAttributes(e.Attributes),
+		}
+	}
+
+	return out
+}
+
+// eventsFromStorage converts a slice of storage.Event back into events.
+func eventsFromStorage(evs []storage.Event) []event {
+	if evs == nil {
+		return nil
+	}
+
+	out := make([]event, len(evs))
+	for i, e := range evs {
+		out[i] = event{
+			Type:       e.Type,
+			Attributes: eventAttributesFromStorage(e.Attributes),
+		}
+	}
+
+	return out
+}
+
+// toStorageEventAttributes converts a slice of eventAttribute into its
+// storage representation.
+func toStorageEventAttributes(attrs []eventAttribute) []storage.EventAttribute {
+	if attrs == nil {
+		return nil
+	}
+
+	out := make([]storage.EventAttribute, len(attrs))
+	for i, a := range attrs {
+		out[i] = storage.EventAttribute{Key: a.Key, Value: a.Value}
+	}
+
+	return out
+}
+
+// eventAttributesFromStorage converts a slice of storage.EventAttribute
+// back into eventAttributes.
+func eventAttributesFromStorage(attrs []storage.EventAttribute) []eventAttribute {
+	if attrs == nil {
+		return nil
+	}
+
+	out := make([]eventAttribute, len(attrs))
+	for i, a := range attrs {
+		out[i] = eventAttribute{Key: a.Key, Value: a.Value}
+	}
+
+	return out
 }
 
 // client is a structure that handles communication with a chain's RPC
-// node.
+// node pool, routing each request to one of the upstreams and failing
+// over around ones that are slow or erroring.
 type client struct {
-	http       *http.Client
-	baseURL    string
-	maxRetries uint64
+	http             *http.Client
+	upstreams        []*upstream
+	strategy         string
+	maxRetries       uint64
+	retryAfter       time.Duration
+	cooldownDuration time.Duration
 
-	cooldownUntilMu sync.RWMutex
-	cooldownUntil   time.Time
+	// rrOrder is a precomputed round-robin visiting order over indices
+	// into upstreams, with each node's index repeated once per unit of
+	// its configured weight, so that a heavier node is picked more often.
+	rrOrder   []int
+	rrCounter uint64
 }
 
-// newClient creates a new instance of client.
-func newClient(baseURL string, maxRetries uint64) *client {
+// newClient creates a new instance of client, pooling the given nodes and
+// routing requests between them according to strategy (one of
+// strategyRoundRobin, strategyLeastLoaded or strategyPriority; priority is
+// given by each node's Priority, lowest first, and weight by each node's
+// Weight, defaulting to 1).
+func newClient(nodes []config.NodeConfig, strategy string, maxRetries uint64, retryAfter, cooldownDuration time.Duration) *client {
+	upstreams := make([]*upstream, len(nodes))
+
+	var rrOrder []int
+
+	for i, n := range nodes {
+		upstreams[i] = &upstream{url: n.URL, priority: n.Priority}
+
+		weight := n.Weight
+		if weight < 1 {
+			weight = 1
+		}
+
+		for w := 0; w < weight; w++ {
+			rrOrder = append(rrOrder, i)
+		}
+	}
+
 	return &client{
 		http: &http.Client{
 			Timeout: time.Minute,
 		},
-		baseURL:    baseURL,
-		maxRetries: maxRetries,
+		upstreams:        upstreams,
+		strategy:         strategy,
+		maxRetries:       maxRetries,
+		retryAfter:       retryAfter,
+		cooldownDuration: cooldownDuration,
+		rrOrder:          rrOrder,
 	}
 }
 
@@ -78,7 +343,7 @@ func newClient(baseURL string, maxRetries uint64) *client {
 // is used by the client.
 func (c *client) fetchNetworkID(ctx context.Context) (string, error) {
 	var statusResp statusResponse
-	err := c.fetchWithRetry(ctx, fmt.Sprintf("%s/status", c.baseURL), &statusResp)
+	err := c.fetchWithRetry(ctx, "/status", &statusResp)
 	if err != nil {
 		return "", err
 	}
@@ -86,65 +351,361 @@ func (c *client) fetchNetworkID(ctx context.Context) (string, error) {
 	return statusResp.Result.NodeInfo.Network, nil
 }
 
-// fetchBlock retrieves the target block's metadata.
-func (c *client) fetchBlock(ctx context.Context, networkID string, height int64) (block, error) {
-	var resp blockResponse
-	err := c.fetchWithRetry(ctx, fmt.Sprintf("%s/block?height=%d", c.baseURL, height), &resp)
+// fetchLatestHeight retrieves the height of the chain's latest block.
+func (c *client) fetchLatestHeight(ctx context.Context) (int64, error) {
+	var statusResp statusResponse
+	if err := c.fetchWithRetry(ctx, "/status", &statusResp); err != nil {
+		return 0, err
+	}
+
+	height, err := strconv.ParseInt(statusResp.Result.SyncInfo.LatestBlockHeight, 10, 64)
 	if err != nil {
+		return 0, fmt.Errorf("parsing latest block height: %w", err)
+	}
+
+	return height, nil
+}
+
+// fetchBlock retrieves the target block's metadata. When includeEvents is
+// true, it additionally fetches the block's /block_results and merges in
+// each transaction's result code, gas figures and events, plus the
+// block's begin/end events.
+func (c *client) fetchBlock(ctx context.Context, networkID string, height int64, includeEvents bool) (block, error) {
+	var resp blockResponse
+	if err := c.fetchWithRetry(ctx, fmt.Sprintf("/block?height=%d", height), &resp); err != nil {
 		return block{}, err
 	}
 
-	return block{
+	b := block{
 		Height:    height,
 		NumTxs:    len(resp.Result.Block.Data.Txs),
 		NetworkID: networkID,
+	}
+
+	if !includeEvents {
+		return b, nil
+	}
+
+	br, err := c.fetchBlockResults(ctx, height)
+	if err != nil {
+		return block{}, err
+	}
+
+	txs, err := mergeTxResults(resp.Result.Block.Data.Txs, br.TxsResults)
+	if err != nil {
+		return block{}, err
+	}
+
+	b.Txs = txs
+	b.BeginBlockEvents = br.BeginBlockEvents
+	b.EndBlockEvents = br.EndBlockEvents
+
+	return b, nil
+}
+
+// fetchBlockResults retrieves the target block's /block_results: each
+// transaction's result code, gas figures and events, plus the block's
+// begin/end events. It does not know the corresponding transactions' raw
+// bytes; mergeTxResults pairs those in once the /block response is also
+// available.
+func (c *client) fetchBlockResults(ctx context.Context, height int64) (blockResults, error) {
+	var resp blockResultsResponse
+	if err := c.fetchWithRetry(ctx, fmt.Sprintf("/block_results?height=%d", height), &resp); err != nil {
+		return blockResults{}, err
+	}
+
+	return buildBlockResults(resp.Result)
+}
+
+// fetchBlocksBatch retrieves metadata for multiple blocks using a single
+// POSTed JSON-RPC 2.0 batch request, correlating each result back to its
+// requested height by its request ID. When includeEvents is true, a
+// "block_results" call is batched alongside each "block" call, and a
+// height is only included in the result once both have been decoded.
+// Blocks that the node returned an error for (or that are missing from the
+// response entirely) are simply omitted from the result and logged, so
+// that the caller can retry just those heights in a subsequent batch; an
+// error is only returned when the batch request itself could not be
+// completed (e.g., the cooldown/retry budget was exhausted).
+func (c *client) fetchBlocksBatch(ctx context.Context, networkID string, heights []int64, includeEvents bool) ([]block, error) {
+	if len(heights) == 0 {
+		return nil, nil
+	}
+
+	reqsPerHeight := 1
+	if includeEvents {
+		reqsPerHeight = 2
+	}
+
+	reqs := make([]jsonRPCRequest, 0, len(heights)*reqsPerHeight)
+
+	for i, height := range heights {
+		reqs = append(reqs, jsonRPCRequest{
+			JSONRPC: "2.0",
+			ID:      i * reqsPerHeight,
+			Method:  "block",
+			Params:  blockHeightParams{Height: fmt.Sprintf("%d", height)},
+		})
+
+		if includeEvents {
+			reqs = append(reqs, jsonRPCRequest{
+				JSONRPC: "2.0",
+				ID:      i*reqsPerHeight + 1,
+				Method:  "block_results",
+				Params:  blockHeightParams{Height: fmt.Sprintf("%d", height)},
+			})
+		}
+	}
+
+	var resps []jsonRPCResponse
+	if err := c.postWithRetry(ctx, "/", reqs, &resps); err != nil {
+		return nil, err
+	}
+
+	blockResps := make(map[int]blockResult, len(heights))
+	resultsResps := make(map[int]blockResultsResult, len(heights))
+
+	for _, resp := range resps {
+		if resp.ID < 0 || resp.ID >= len(heights)*reqsPerHeight {
+			log.Printf("Batch block fetch returned an unexpected response id %d\n", resp.ID)
+
+			continue
+		}
+
+		idx := resp.ID / reqsPerHeight
+
+		if resp.Error != nil {
+			log.Printf("Batch block fetch failed for height %d: %s\n", heights[idx], resp.Error.Message)
+
+			continue
+		}
+
+		if includeEvents && resp.ID%reqsPerHeight == 1 {
+			var result blockResultsResult
+			if err := json.Unmarshal(resp.Result, &result); err != nil {
+				log.Printf("Batch block fetch failed to decode block results for height %d: %s\n", heights[idx], err)
+
+				continue
+			}
+
+			resultsResps[idx] = result
+
+			continue
+		}
+
+		var result blockResult
+		if err := json.Unmarshal(resp.Result, &result); err != nil {
+			log.Printf("Batch block fetch failed to decode height %d: %s\n", heights[idx], err)
+
+			continue
+		}
+
+		blockResps[idx] = result
+	}
+
+	blocks := make([]block, 0, len(heights))
+
+	for i, height := range heights {
+		br, ok := blockResps[i]
+		if !ok {
+			continue
+		}
+
+		b := block{
+			Height:    height,
+			NumTxs:    len(br.Block.Data.Txs),
+			NetworkID: networkID,
+		}
+
+		if includeEvents {
+			rr, ok := resultsResps[i]
+			if !ok {
+				continue
+			}
+
+			decoded, err := buildBlockResults(rr)
+			if err != nil {
+				log.Printf("Batch block fetch failed to parse block results for height %d: %s\n", height, err)
+
+				continue
+			}
+
+			txs, err := mergeTxResults(br.Block.Data.Txs, decoded.TxsResults)
+			if err != nil {
+				log.Printf("Batch block fetch failed to decode tx bytes for height %d: %s\n", height, err)
+
+				continue
+			}
+
+			b.Txs = txs
+			b.BeginBlockEvents = decoded.BeginBlockEvents
+			b.EndBlockEvents = decoded.EndBlockEvents
+		}
+
+		blocks = append(blocks, b)
+	}
+
+	return blocks, nil
+}
+
+// buildBlockResults converts a blockResultsResult, as decoded from JSON,
+// into a blockResults, parsing its string-encoded gas figures into
+// integers.
+func buildBlockResults(r blockResultsResult) (blockResults, error) {
+	txResults := make([]txResult, len(r.TxsResults))
+
+	for i, tr := range r.TxsResults {
+		gasWanted, err := parseOptionalInt64(tr.GasWanted)
+		if err != nil {
+			return blockResults{}, fmt.Errorf("parsing gas_wanted: %w", err)
+		}
+
+		gasUsed, err := parseOptionalInt64(tr.GasUsed)
+		if err != nil {
+			return blockResults{}, fmt.Errorf("parsing gas_used: %w", err)
+		}
+
+		txResults[i] = txResult{
+			Code:      tr.Code,
+			GasWanted: gasWanted,
+			GasUsed:   gasUsed,
+			Events:    tr.Events,
+		}
+	}
+
+	return blockResults{
+		TxsResults:       txResults,
+		BeginBlockEvents: r.BeginBlockEvents,
+		EndBlockEvents:   r.EndBlockEvents,
 	}, nil
 }
 
-// fetchWithRetry retrieves the target resource and applies a repeated retry
-// strategy if needed.
-func (c *client) fetchWithRetry(ctx context.Context, targetURL string, target interface{}) error {
-	req, err := http.NewRequest(http.MethodGet, targetURL, http.NoBody)
-	if err != nil {
-		return err
+// mergeTxResults pairs each base64-encoded tx from a /block response with
+// its matching entry (by index) in a /block_results response's
+// txs_results, decoding the tx bytes along the way. A height whose two
+// responses disagree on transaction count is not expected to occur in
+// practice, so any txs beyond the shorter of the two are left with their
+// zero-value result fields.
+func mergeTxResults(rawTxs []string, results []txResult) ([]txResult, error) {
+	txs := make([]txResult, len(rawTxs))
+
+	for i, raw := range rawTxs {
+		txBytes, err := base64.StdEncoding.DecodeString(raw)
+		if err != nil {
+			return nil, fmt.Errorf("decoding tx bytes: %w", err)
+		}
+
+		txs[i] = txResult{Tx: txBytes}
+
+		if i < len(results) {
+			txs[i].Code = results[i].Code
+			txs[i].GasWanted = results[i].GasWanted
+			txs[i].GasUsed = results[i].GasUsed
+			txs[i].Events = results[i].Events
+		}
+	}
+
+	return txs, nil
+}
+
+// parseOptionalInt64 parses s as a base-10 int64, treating an empty
+// string (as Tendermint/CometBFT omits zero-valued gas figures) as 0.
+func parseOptionalInt64(s string) (int64, error) {
+	if s == "" {
+		return 0, nil
+	}
+
+	return strconv.ParseInt(s, 10, 64)
+}
+
+// fetchWithRetry retrieves path from one of the pool's upstreams with a
+// GET request and applies a repeated retry strategy if needed, failing
+// over to another upstream when the one picked is slow or erroring.
+func (c *client) fetchWithRetry(ctx context.Context, path string, target interface{}) error {
+	return c.doWithRetry(ctx, http.MethodGet, path, nil, target)
+}
+
+// postWithRetry POSTs body as JSON to path on one of the pool's upstreams
+// and applies the same repeated retry strategy as fetchWithRetry.
+func (c *client) postWithRetry(ctx context.Context, path string, body interface{}, target interface{}) error {
+	return c.doWithRetry(ctx, http.MethodPost, path, body, target)
+}
+
+// doWithRetry picks an upstream for each attempt, issues method+path
+// against it, and decodes the JSON response body into target. Non-429
+// errors and timeouts take the picked upstream out of rotation for a
+// growing backoff window; a 429 instead cools down just that upstream for
+// the fixed cooldown duration, mirroring the client-wide cooldown this used
+// to apply before the pool existed. Retries continue, possibly against a
+// different upstream, until maxRetries is exhausted or ctx is cancelled.
+func (c *client) doWithRetry(ctx context.Context, method, path string, body, target interface{}) error {
+	var data []byte
+
+	if body != nil {
+		var err error
+
+		data, err = json.Marshal(body)
+		if err != nil {
+			return err
+		}
 	}
 
 	return backoff.RetryNotify(func() error {
-		c.cooldownUntilMu.RLock()
-		if time.Now().Before(c.cooldownUntil) {
-			select {
-			case <-time.After(c.cooldownUntil.Sub(time.Now())):
-			case <-ctx.Done():
-				return ctx.Err()
-			}
+		u, err := c.pickUpstream()
+		if err != nil {
+			return err
 		}
-		c.cooldownUntilMu.RUnlock()
 
-		resp, err := c.http.Do(req.WithContext(ctx))
+		reqBody := io.Reader(http.NoBody)
+		if data != nil {
+			reqBody = bytes.NewReader(data)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, method, u.url+path, reqBody)
 		if err != nil {
+			return backoff.Permanent(err)
+		}
+
+		if data != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+
+		u.incInFlight()
+		resp, err := c.http.Do(req)
+		u.decInFlight()
+
+		if err != nil {
+			u.recordFailure(time.Now(), c.cooldownDuration)
+
 			return err
 		}
 
 		defer resp.Body.Close()
 
 		if resp.StatusCode == 429 {
-			c.cooldownUntilMu.Lock()
-			c.cooldownUntil = time.Now().Add(cooldownDuration)
-			c.cooldownUntilMu.Unlock()
+			u.cooldown(time.Now(), c.cooldownDuration)
 
 			return errCooldown
 		}
 
+		if resp.StatusCode >= 500 {
+			u.recordFailure(time.Now(), c.cooldownDuration)
+
+			return fmt.Errorf("upstream %s responded with status %d", u.url, resp.StatusCode)
+		}
+
+		u.recordSuccess()
+
 		return json.NewDecoder(resp.Body).Decode(target)
 	}, backoff.WithContext(
 		backoff.WithMaxRetries(
-			backoff.NewConstantBackOff(retryAfter),
+			backoff.NewConstantBackOff(c.retryAfter),
 			c.maxRetries,
 		),
 		ctx,
 	), func(err error, d time.Duration) {
 		if !errors.Is(err, errCooldown) {
-			log.Printf("Retrying block fetch request in %s (%s)\n", d, err)
+			log.Printf("Retrying request in %s (%s)\n", d, err)
 		}
 	})
 }
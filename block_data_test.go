@@ -34,7 +34,7 @@ func Test_client_fetchNetworkID(t *testing.T) {
 			mt := httpmock.NewMockTransport()
 			c := client{
 				http:       &http.Client{Transport: mt},
-				baseURL:    "http://localhost",
+				upstreams:  []*upstream{{url: "http://localhost"}},
 				maxRetries: 1,
 			}
 			mt.RegisterResponder(http.MethodGet, "http://localhost/status", tcase.Responder)
@@ -53,9 +53,11 @@ func Test_client_fetchNetworkID(t *testing.T) {
 
 func Test_client_fetchBlock(t *testing.T) {
 	tests := map[string]struct {
-		Responder httpmock.Responder
-		Result    block
-		Error     bool
+		Responder             httpmock.Responder
+		BlockResultsResponder httpmock.Responder
+		IncludeEvents         bool
+		Result                block
+		Error                 bool
 	}{
 		"Request error": {
 			Responder: httpmock.NewErrorResponder(assert.AnError),
@@ -63,7 +65,7 @@ func Test_client_fetchBlock(t *testing.T) {
 			Error:     true,
 		},
 		"Successfully fetched": {
-			Responder: httpmock.NewStringResponder(http.StatusOK, `{"result":{"block": {"data":{"txs":[{}, {}, {}]}}}}`),
+			Responder: httpmock.NewStringResponder(http.StatusOK, `{"result":{"block": {"data":{"txs":["AAA=", "AQE=", "AgI="]}}}}`),
 			Result: block{
 				NetworkID: "test",
 				Height:    123,
@@ -71,6 +73,31 @@ func Test_client_fetchBlock(t *testing.T) {
 			},
 			Error: false,
 		},
+		"Successfully fetched with events": {
+			Responder: httpmock.NewStringResponder(http.StatusOK, `{"result":{"block": {"data":{"txs":["AAA="]}}}}`),
+			BlockResultsResponder: httpmock.NewStringResponder(http.StatusOK, `{"result":{
+				"txs_results": [{"code":0,"gas_wanted":"100","gas_used":"90","events":[{"type":"transfer","attributes":[{"key":"amount","value":"1"}]}]}],
+				"begin_block_events": [{"type":"mint","attributes":[]}],
+				"end_block_events": []
+			}}`),
+			IncludeEvents: true,
+			Result: block{
+				NetworkID: "test",
+				Height:    123,
+				NumTxs:    1,
+				Txs: []txResult{
+					{
+						Tx:        []byte{0x00, 0x00},
+						GasWanted: 100,
+						GasUsed:   90,
+						Events:    []event{{Type: "transfer", Attributes: []eventAttribute{{Key: "amount", Value: "1"}}}},
+					},
+				},
+				BeginBlockEvents: []event{{Type: "mint", Attributes: []eventAttribute{}}},
+				EndBlockEvents:   []event{},
+			},
+			Error: false,
+		},
 	}
 
 	for tname, tcase := range tests {
@@ -80,12 +107,158 @@ func Test_client_fetchBlock(t *testing.T) {
 			mt := httpmock.NewMockTransport()
 			c := client{
 				http:       &http.Client{Transport: mt},
-				baseURL:    "http://localhost",
+				upstreams:  []*upstream{{url: "http://localhost"}},
 				maxRetries: 1,
 			}
 			mt.RegisterResponder(http.MethodGet, "http://localhost/block", tcase.Responder)
 
-			res, err := c.fetchBlock(context.Background(), "test", 123)
+			if tcase.BlockResultsResponder != nil {
+				mt.RegisterResponder(http.MethodGet, "http://localhost/block_results", tcase.BlockResultsResponder)
+			}
+
+			res, err := c.fetchBlock(context.Background(), "test", 123, tcase.IncludeEvents)
+			if tcase.Error {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tcase.Result, res)
+		})
+	}
+}
+
+func Test_client_fetchBlocksBatch(t *testing.T) {
+	tests := map[string]struct {
+		Responder     httpmock.Responder
+		IncludeEvents bool
+		Result        []block
+		Error         bool
+	}{
+		"Request error": {
+			Responder: httpmock.NewErrorResponder(assert.AnError),
+			Result:    nil,
+			Error:     true,
+		},
+		"Partial failure is omitted": {
+			Responder: httpmock.NewStringResponder(http.StatusOK, `[
+				{"id":0,"result":{"block":{"data":{"txs":["AAA="]}}}},
+				{"id":1,"error":{"code":1,"message":"height not available"}}
+			]`),
+			Result: []block{
+				{NetworkID: "test", Height: 10, NumTxs: 1},
+			},
+			Error: false,
+		},
+		"Successfully fetched": {
+			Responder: httpmock.NewStringResponder(http.StatusOK, `[
+				{"id":0,"result":{"block":{"data":{"txs":["AAA=", "AQE="]}}}},
+				{"id":1,"result":{"block":{"data":{"txs":[]}}}}
+			]`),
+			Result: []block{
+				{NetworkID: "test", Height: 10, NumTxs: 2},
+				{NetworkID: "test", Height: 11, NumTxs: 0},
+			},
+			Error: false,
+		},
+		"Successfully fetched with events": {
+			IncludeEvents: true,
+			Responder: httpmock.NewStringResponder(http.StatusOK, `[
+				{"id":0,"result":{"block":{"data":{"txs":["AAA="]}}}},
+				{"id":1,"result":{"txs_results":[{"code":0,"gas_wanted":"100","gas_used":"90","events":[]}]}},
+				{"id":2,"result":{"block":{"data":{"txs":[]}}}},
+				{"id":3,"result":{"txs_results":[]}}
+			]`),
+			Result: []block{
+				{
+					NetworkID: "test",
+					Height:    10,
+					NumTxs:    1,
+					Txs: []txResult{
+						{Tx: []byte{0x00, 0x00}, GasWanted: 100, GasUsed: 90, Events: []event{}},
+					},
+				},
+				{NetworkID: "test", Height: 11, NumTxs: 0, Txs: []txResult{}},
+			},
+			Error: false,
+		},
+	}
+
+	for tname, tcase := range tests {
+		t.Run(tname, func(t *testing.T) {
+			t.Parallel()
+
+			mt := httpmock.NewMockTransport()
+			c := client{
+				http:       &http.Client{Transport: mt},
+				upstreams:  []*upstream{{url: "http://localhost"}},
+				maxRetries: 1,
+			}
+			mt.RegisterResponder(http.MethodPost, "http://localhost/", tcase.Responder)
+
+			res, err := c.fetchBlocksBatch(context.Background(), "test", []int64{10, 11}, tcase.IncludeEvents)
+			if tcase.Error {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			assert.Equal(t, tcase.Result, res)
+		})
+	}
+}
+
+func Test_client_fetchBlockResults(t *testing.T) {
+	tests := map[string]struct {
+		Responder httpmock.Responder
+		Result    blockResults
+		Error     bool
+	}{
+		"Request error": {
+			Responder: httpmock.NewErrorResponder(assert.AnError),
+			Result:    blockResults{},
+			Error:     true,
+		},
+		"Invalid gas figure": {
+			Responder: httpmock.NewStringResponder(http.StatusOK, `{"result":{"txs_results":[{"gas_wanted":"not-a-number"}]}}`),
+			Result:    blockResults{},
+			Error:     true,
+		},
+		"Successfully fetched": {
+			Responder: httpmock.NewStringResponder(http.StatusOK, `{"result":{
+				"txs_results": [{"code":5,"gas_wanted":"100","gas_used":"90","events":[{"type":"transfer","attributes":[{"key":"amount","value":"1"}]}]}],
+				"begin_block_events": [{"type":"mint","attributes":[]}],
+				"end_block_events": [{"type":"distribute","attributes":[]}]
+			}}`),
+			Result: blockResults{
+				TxsResults: []txResult{
+					{
+						Code:      5,
+						GasWanted: 100,
+						GasUsed:   90,
+						Events:    []event{{Type: "transfer", Attributes: []eventAttribute{{Key: "amount", Value: "1"}}}},
+					},
+				},
+				BeginBlockEvents: []event{{Type: "mint", Attributes: []eventAttribute{}}},
+				EndBlockEvents:   []event{{Type: "distribute", Attributes: []eventAttribute{}}},
+			},
+			Error: false,
+		},
+	}
+
+	for tname, tcase := range tests {
+		t.Run(tname, func(t *testing.T) {
+			t.Parallel()
+
+			mt := httpmock.NewMockTransport()
+			c := client{
+				http:       &http.Client{Transport: mt},
+				upstreams:  []*upstream{{url: "http://localhost"}},
+				maxRetries: 1,
+			}
+			mt.RegisterResponder(http.MethodGet, "http://localhost/block_results", tcase.Responder)
+
+			res, err := c.fetchBlockResults(context.Background(), 123)
 			if tcase.Error {
 				assert.Error(t, err)
 			} else {
@@ -0,0 +1,215 @@
+// Package config defines the YAML file format accepted by --config, so
+// that an indexing job (node pool, output format, retry tuning, and the
+// set of height ranges to fetch) can be committed to version control and
+// reproduced instead of being re-typed as a long flag line every run.
+package config
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats accepted by Output.Format.
+//
+// A "sqlite" format was requested alongside these, but no writer for it
+// exists yet; rather than accept it here and fail only once export runs
+// at the end of a job, Validate rejects it up front. Revisit once a
+// sqlite writer lands, or drop the idea from the request for good.
+const (
+	FormatJSON   = "json"
+	FormatNDJSON = "ndjson"
+	FormatBolt   = "bolt"
+)
+
+// Default values applied to any field left unset in a loaded config, and
+// used as the CLI flags' own defaults so that the two stay in sync.
+const (
+	DefaultParallelism      = 4
+	DefaultBatchSize        = 20
+	DefaultOutputFormat     = FormatJSON
+	DefaultMaxRetries       = 100
+	DefaultRetryAfter       = time.Second
+	DefaultCooldownDuration = time.Second * 5
+)
+
+// NodeConfig describes a single RPC endpoint in the pool.
+type NodeConfig struct {
+	URL string `yaml:"url"`
+
+	// Priority ranks the node for the "priority" --rpc-strategy: lower
+	// values are preferred. Defaults to the node's position in the list.
+	Priority int `yaml:"priority"`
+
+	// Weight controls how often the node is picked relative to its
+	// peers under the "round-robin" --rpc-strategy. Defaults to 1.
+	Weight int `yaml:"weight"`
+}
+
+// OutputConfig describes where and how indexed blocks are exported once a
+// run finishes.
+type OutputConfig struct {
+	// Format is one of FormatJSON, FormatNDJSON or FormatBolt.
+	Format string `yaml:"format"`
+	Path   string `yaml:"path"`
+}
+
+// RetryConfig tunes the client's retry/backoff behavior. RetryAfter and
+// CooldownDuration are written as duration strings in YAML (e.g. "5s"),
+// so RetryConfig marshals and unmarshals itself rather than relying on
+// yaml.v3's default int64 handling of time.Duration.
+type RetryConfig struct {
+	MaxRetries       uint64
+	RetryAfter       time.Duration
+	CooldownDuration time.Duration
+}
+
+// rawRetryConfig is RetryConfig's YAML representation. Every field is a
+// pointer so that UnmarshalYAML can tell an explicitly-set zero value
+// apart from an omitted one, leaving the latter at its Default().
+type rawRetryConfig struct {
+	MaxRetries       *uint64 `yaml:"max_retries"`
+	RetryAfter       *string `yaml:"retry_after"`
+	CooldownDuration *string `yaml:"cooldown_duration"`
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (r *RetryConfig) UnmarshalYAML(value *yaml.Node) error {
+	var raw rawRetryConfig
+	if err := value.Decode(&raw); err != nil {
+		return err
+	}
+
+	if raw.MaxRetries != nil {
+		r.MaxRetries = *raw.MaxRetries
+	}
+
+	if raw.RetryAfter != nil {
+		d, err := time.ParseDuration(*raw.RetryAfter)
+		if err != nil {
+			return fmt.Errorf("parsing retry_after: %w", err)
+		}
+
+		r.RetryAfter = d
+	}
+
+	if raw.CooldownDuration != nil {
+		d, err := time.ParseDuration(*raw.CooldownDuration)
+		if err != nil {
+			return fmt.Errorf("parsing cooldown_duration: %w", err)
+		}
+
+		r.CooldownDuration = d
+	}
+
+	return nil
+}
+
+// MarshalYAML implements yaml.Marshaler.
+func (r RetryConfig) MarshalYAML() (interface{}, error) {
+	return struct {
+		MaxRetries       uint64 `yaml:"max_retries"`
+		RetryAfter       string `yaml:"retry_after"`
+		CooldownDuration string `yaml:"cooldown_duration"`
+	}{
+		MaxRetries:       r.MaxRetries,
+		RetryAfter:       r.RetryAfter.String(),
+		CooldownDuration: r.CooldownDuration.String(),
+	}, nil
+}
+
+// RangeConfig describes a single, disjoint height window to index.
+// NetworkID overrides the network ID blocks in this range are saved
+// under; left empty, the network ID reported by the node pool is used.
+// This mainly exists for chains that changed chain-id at a known height
+// (a common Cosmos hard-fork pattern), letting one job cover every era.
+type RangeConfig struct {
+	Start     int64  `yaml:"start"`
+	End       int64  `yaml:"end"`
+	NetworkID string `yaml:"network_id,omitempty"`
+}
+
+// Config is the root of a --config YAML file.
+type Config struct {
+	Nodes       []NodeConfig  `yaml:"nodes"`
+	Parallelism int           `yaml:"parallelism"`
+	BatchSize   int           `yaml:"batch_size"`
+	Output      OutputConfig  `yaml:"output"`
+	Retry       RetryConfig   `yaml:"retry"`
+	Ranges      []RangeConfig `yaml:"ranges"`
+}
+
+// Default returns a Config with every field set to its default value, to
+// be loaded from a file (or mutated by CLI flag overrides) on top of.
+func Default() Config {
+	return Config{
+		Parallelism: DefaultParallelism,
+		BatchSize:   DefaultBatchSize,
+		Output: OutputConfig{
+			Format: DefaultOutputFormat,
+		},
+		Retry: RetryConfig{
+			MaxRetries:       DefaultMaxRetries,
+			RetryAfter:       DefaultRetryAfter,
+			CooldownDuration: DefaultCooldownDuration,
+		},
+	}
+}
+
+// Load reads and parses the YAML config file at path on top of Default().
+func Load(path string) (Config, error) {
+	cfg := Default()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("reading config file: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return Config{}, fmt.Errorf("parsing config file: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// Validate checks that cfg is complete and internally consistent enough to
+// start an indexing run.
+func (cfg Config) Validate() error {
+	if len(cfg.Nodes) == 0 {
+		return fmt.Errorf("at least one node is required")
+	}
+
+	for i, n := range cfg.Nodes {
+		if n.URL == "" {
+			return fmt.Errorf("nodes[%d]: url is required", i)
+		}
+	}
+
+	switch cfg.Output.Format {
+	case FormatJSON, FormatNDJSON, FormatBolt:
+	default:
+		return fmt.Errorf("output.format must be one of %s, %s or %s", FormatJSON, FormatNDJSON, FormatBolt)
+	}
+
+	if cfg.Parallelism < 1 {
+		return fmt.Errorf("parallelism must be 1 or greater")
+	}
+
+	if cfg.BatchSize < 1 {
+		return fmt.Errorf("batch_size must be 1 or greater")
+	}
+
+	for i, r := range cfg.Ranges {
+		if r.Start < 0 {
+			return fmt.Errorf("ranges[%d]: start must be 0 or greater", i)
+		}
+
+		if r.End < r.Start {
+			return fmt.Errorf("ranges[%d]: end must be greater than or equal to start", i)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,140 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func Test_RetryConfig_UnmarshalYAML(t *testing.T) {
+	tests := map[string]struct {
+		YAML   string
+		Result RetryConfig
+		Error  bool
+	}{
+		"Empty document leaves every field at its zero value": {
+			YAML:   `{}`,
+			Result: RetryConfig{},
+		},
+		"Every field set": {
+			YAML: `
+max_retries: 10
+retry_after: 5s
+cooldown_duration: 1m`,
+			Result: RetryConfig{
+				MaxRetries:       10,
+				RetryAfter:       time.Second * 5,
+				CooldownDuration: time.Minute,
+			},
+		},
+		"Only max_retries set leaves the durations zero": {
+			YAML:   `max_retries: 3`,
+			Result: RetryConfig{MaxRetries: 3},
+		},
+		"Malformed retry_after duration string": {
+			YAML:  `retry_after: not-a-duration`,
+			Error: true,
+		},
+		"Malformed cooldown_duration duration string": {
+			YAML:  `cooldown_duration: not-a-duration`,
+			Error: true,
+		},
+	}
+
+	for tname, tcase := range tests {
+		t.Run(tname, func(t *testing.T) {
+			t.Parallel()
+
+			var r RetryConfig
+
+			err := yaml.Unmarshal([]byte(tcase.YAML), &r)
+			if tcase.Error {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tcase.Result, r)
+		})
+	}
+}
+
+func Test_RetryConfig_MarshalYAML_roundTrip(t *testing.T) {
+	want := RetryConfig{
+		MaxRetries:       7,
+		RetryAfter:       time.Second * 2,
+		CooldownDuration: time.Second * 30,
+	}
+
+	data, err := yaml.Marshal(want)
+	assert.NoError(t, err)
+
+	var got RetryConfig
+
+	assert.NoError(t, yaml.Unmarshal(data, &got))
+	assert.Equal(t, want, got)
+}
+
+func Test_Config_Validate(t *testing.T) {
+	validConfig := func() Config {
+		cfg := Default()
+		cfg.Nodes = []NodeConfig{{URL: "http://localhost"}}
+
+		return cfg
+	}
+
+	tests := map[string]struct {
+		MutateConfig func(*Config)
+		Error        bool
+	}{
+		"Valid config": {
+			MutateConfig: func(cfg *Config) {},
+		},
+		"No nodes": {
+			MutateConfig: func(cfg *Config) { cfg.Nodes = nil },
+			Error:        true,
+		},
+		"Node missing a URL": {
+			MutateConfig: func(cfg *Config) { cfg.Nodes[0].URL = "" },
+			Error:        true,
+		},
+		"Unsupported output format": {
+			MutateConfig: func(cfg *Config) { cfg.Output.Format = "sqlite" },
+			Error:        true,
+		},
+		"Parallelism below 1": {
+			MutateConfig: func(cfg *Config) { cfg.Parallelism = 0 },
+			Error:        true,
+		},
+		"BatchSize below 1": {
+			MutateConfig: func(cfg *Config) { cfg.BatchSize = 0 },
+			Error:        true,
+		},
+		"Range end before start": {
+			MutateConfig: func(cfg *Config) { cfg.Ranges = []RangeConfig{{Start: 10, End: 5}} },
+			Error:        true,
+		},
+		"Range with a negative start": {
+			MutateConfig: func(cfg *Config) { cfg.Ranges = []RangeConfig{{Start: -1, End: 5}} },
+			Error:        true,
+		},
+	}
+
+	for tname, tcase := range tests {
+		t.Run(tname, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := validConfig()
+			tcase.MutateConfig(&cfg)
+
+			err := cfg.Validate()
+			if tcase.Error {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"cosmos-blocks/storage"
+)
+
+// runFollow back-fills every block from startHeight up to the chain's
+// current tip using the regular worker pool, then switches to the node's
+// websocket stream and keeps saving new blocks as they are produced. It
+// returns nil once ctx is cancelled (e.g., on SIGINT).
+func runFollow(ctx context.Context, cl *client, store storage.Store, networkID string, startHeight int64, parallelism, batchSize int, includeEvents bool) error {
+	tip, err := cl.fetchLatestHeight(ctx)
+	if err != nil {
+		return err
+	}
+
+	if startHeight <= tip {
+		log.Printf("Back-filling blocks %d through %d before following new ones\n", startHeight, tip)
+
+		if err := indexBlocks(ctx, cl, store, networkID, startHeight, tip, parallelism, batchSize, includeEvents); err != nil {
+			return err
+		}
+	}
+
+	lastHeight := tip
+
+	for ctx.Err() == nil {
+		latestTip, err := cl.fetchLatestHeight(ctx)
+		if err == nil && latestTip > lastHeight {
+			log.Printf("Filling gap from %d to %d before (re)subscribing\n", lastHeight+1, latestTip)
+
+			if err := indexBlocks(ctx, cl, store, networkID, lastHeight+1, latestTip, parallelism, batchSize, includeEvents); err != nil {
+				return err
+			}
+
+			lastHeight = latestTip
+		}
+
+		blockCh, err := cl.subscribeNewBlocks(ctx, networkID, includeEvents)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			log.Printf("Failed to subscribe to new blocks, retrying in %s (%s)\n", cl.retryAfter, err)
+
+			select {
+			case <-time.After(cl.retryAfter):
+			case <-ctx.Done():
+				return nil
+			}
+
+			continue
+		}
+
+		log.Println("Following new blocks")
+
+		for b := range blockCh {
+			if err := store.SaveBlock(b.toStorageBlock()); err != nil {
+				return err
+			}
+
+			lastHeight = b.Height
+		}
+	}
+
+	return nil
+}
@@ -4,60 +4,187 @@ import (
 	"context"
 	"encoding/json"
 	"flag"
+	"fmt"
 	"log"
 	"os"
 	"os/signal"
-	"sort"
+	"strings"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/schollz/progressbar/v3"
+	"gopkg.in/yaml.v3"
+
+	"cosmos-blocks/config"
+	"cosmos-blocks/storage"
 )
 
+// maxBatchItemRetries is the number of times a single height is re-sent
+// in a batch after the node returned an error (or omitted it) for it,
+// before giving up and failing the whole run.
+const maxBatchItemRetries = 3
+
+// nodeURLList collects one or more --node-url flags, each of which may
+// itself hold a comma-separated list of URLs, into a single flat slice.
+type nodeURLList []string
+
+// String implements flag.Value.
+func (l *nodeURLList) String() string {
+	return strings.Join(*l, ",")
+}
+
+// Set implements flag.Value.
+func (l *nodeURLList) Set(value string) error {
+	for _, u := range strings.Split(value, ",") {
+		if u = strings.TrimSpace(u); u != "" {
+			*l = append(*l, u)
+		}
+	}
+
+	return nil
+}
+
 func main() {
 	var (
-		startHeight int64
-		endHeight   int64
-		nodeURL     string
-		parallelism int
-		outputFile  string
+		startHeight      int64
+		endHeight        int64
+		nodeURLs         nodeURLList
+		rpcStrategy      string
+		parallelism      int
+		outputFile       string
+		outputFormat     string
+		storePath        string
+		resume           bool
+		batchSize        int
+		follow           bool
+		includeEvents    bool
+		configPath       string
+		printConfig      bool
+		maxRetries       uint64
+		retryAfter       time.Duration
+		cooldownDuration time.Duration
 	)
 
 	flag.Int64Var(&startHeight, "start-height", -1, "The height of the first fetched block")
 	flag.Int64Var(&endHeight, "end-height", -1, "The height of the last fetched block")
-	flag.StringVar(&nodeURL, "node-url", "", "The base URL of Cosmos RPC node")
-	flag.IntVar(&parallelism, "parallelism", -1, "The number of concurrent workers to use when fetching block data")
-	flag.StringVar(&outputFile, "output", "", "The path to the output file (e.g., /absolute/path/to/file)")
+	flag.Var(&nodeURLs, "node-url", "The base URL of a Cosmos RPC node; may be repeated, or a single flag may hold a comma-separated list, to pool several upstreams")
+	flag.StringVar(&rpcStrategy, "rpc-strategy", strategyRoundRobin, "The strategy used to pick an upstream for each request: round-robin, least-loaded or priority")
+	flag.IntVar(&parallelism, "parallelism", config.DefaultParallelism, "The number of concurrent workers to use when fetching block data")
+	flag.StringVar(&outputFile, "output", "", "The path to the output file (e.g., /absolute/path/to/file); left empty, exporting is skipped")
+	flag.StringVar(&outputFormat, "output-format", config.DefaultOutputFormat, "The format used for --output: json, ndjson or bolt")
+	flag.StringVar(&storePath, "store-path", "indexer-db", "The path to the persistent indexer store")
+	flag.BoolVar(&resume, "resume", false, "Resume indexing from the last height saved in the store instead of --start-height")
+	flag.IntVar(&batchSize, "batch-size", config.DefaultBatchSize, "The number of blocks to fetch per JSON-RPC batch request")
+	flag.BoolVar(&follow, "follow", false, "Back-fill from --start-height, then keep following new blocks as they are produced")
+	flag.BoolVar(&includeEvents, "include-events", true, "Fetch /block_results and store each transaction's result code, gas figures and events, plus the block's begin/end events; set to false for a lighter payload")
+	flag.StringVar(&configPath, "config", "", "The path to a YAML config file; CLI flags take precedence over any value it sets")
+	flag.BoolVar(&printConfig, "print-config", false, "Print the effective merged config as YAML and exit, without indexing anything")
+	flag.Uint64Var(&maxRetries, "max-retries", config.DefaultMaxRetries, "The number of times a request is retried against the upstream pool before giving up")
+	flag.DurationVar(&retryAfter, "retry-after", config.DefaultRetryAfter, "How long to wait between retries of the same request")
+	flag.DurationVar(&cooldownDuration, "cooldown-duration", config.DefaultCooldownDuration, "The base quarantine window applied to an upstream after a failure")
 	flag.Parse()
 
-	if startHeight < 0 {
+	cfg := config.Default()
+
+	if configPath != "" {
+		loaded, err := config.Load(configPath)
+		if err != nil {
+			log.Fatal("There was an error loading the config file:", err)
+		}
+
+		cfg = loaded
+	}
+
+	set := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) { set[f.Name] = true })
+
+	if len(nodeURLs) > 0 {
+		nodes := make([]config.NodeConfig, len(nodeURLs))
+
+		for i, u := range nodeURLs {
+			nodes[i] = config.NodeConfig{URL: u, Priority: i, Weight: 1}
+		}
+
+		cfg.Nodes = nodes
+	}
+
+	if set["parallelism"] {
+		cfg.Parallelism = parallelism
+	}
+
+	if set["batch-size"] {
+		cfg.BatchSize = batchSize
+	}
+
+	if set["output"] {
+		cfg.Output.Path = outputFile
+	}
+
+	if set["output-format"] {
+		cfg.Output.Format = outputFormat
+	}
+
+	if set["max-retries"] {
+		cfg.Retry.MaxRetries = maxRetries
+	}
+
+	if set["retry-after"] {
+		cfg.Retry.RetryAfter = retryAfter
+	}
+
+	if set["cooldown-duration"] {
+		cfg.Retry.CooldownDuration = cooldownDuration
+	}
+
+	if printConfig {
+		if err := cfg.Validate(); err != nil {
+			log.Fatal("Effective config is invalid:", err)
+		}
+
+		enc := yaml.NewEncoder(os.Stdout)
+		defer enc.Close()
+
+		if err := enc.Encode(cfg); err != nil {
+			log.Fatal("There was an error printing the effective config:", err)
+		}
+
+		return
+	}
+
+	if err := cfg.Validate(); err != nil {
+		log.Fatal("Invalid config:", err)
+	}
+
+	if !resume && startHeight < 0 && len(cfg.Ranges) == 0 {
 		log.Fatal("--start-height must be 0 or greater")
 	}
 
-	if endHeight < 0 || endHeight < startHeight {
-		log.Fatal("--end-height must be greater or equal to start height")
+	switch rpcStrategy {
+	case strategyRoundRobin, strategyLeastLoaded, strategyPriority:
+	default:
+		log.Fatal("--rpc-strategy must be one of round-robin, least-loaded or priority")
 	}
 
-	if nodeURL == "" {
-		log.Fatal("--node-url must be provided")
+	if follow && endHeight >= 0 {
+		log.Fatal("--end-height must not be set when --follow is used")
 	}
 
-	if parallelism < 1 {
-		log.Fatal("--parallelism must be 1 or greater")
+	if len(cfg.Ranges) > 0 && (follow || resume) {
+		log.Fatal("ranges in the config file cannot be combined with --follow or --resume")
 	}
 
-	if outputFile == "" {
-		log.Fatal("--output must be provided")
+	store, err := storage.NewBoltStore(storePath)
+	if err != nil {
+		log.Fatal("There was an error opening the indexer store:", err)
 	}
 
+	defer store.Close()
+
 	var (
-		wg                  sync.WaitGroup
-		blockCh             = make(chan int64, parallelism)
-		resultCh            = make(chan block, endHeight-startHeight+1)
 		term                = make(chan os.Signal, 1)
 		rootCtx, rootCancel = context.WithCancel(context.Background())
-		cl                  = newClient(nodeURL, maxRetries)
-		bar                 = progressbar.Default(endHeight - startHeight + 1)
+		cl                  = newClient(cfg.Nodes, rpcStrategy, cfg.Retry.MaxRetries, cfg.Retry.RetryAfter, cfg.Retry.CooldownDuration)
 	)
 
 	signal.Notify(term, syscall.SIGINT, syscall.SIGTERM)
@@ -67,74 +194,232 @@ func main() {
 		rootCancel()
 	}()
 
+	cl.startHealthChecks(rootCtx)
+	cl.startStatsLogging(rootCtx)
+
 	networkID, err := cl.fetchNetworkID(rootCtx)
 	if err != nil {
 		log.Fatal("There was an error fetching network status info:", err)
 	}
 
+	if resume {
+		latest, lerr := store.LatestHeight(networkID)
+		if lerr != nil {
+			log.Fatal("There was an error reading the latest saved height:", lerr)
+		}
+
+		startHeight = latest + 1
+
+		log.Println("Resuming from height", startHeight)
+	}
+
+	switch {
+	case len(cfg.Ranges) > 0:
+		for _, r := range cfg.Ranges {
+			rangeNetworkID := networkID
+			if r.NetworkID != "" {
+				rangeNetworkID = r.NetworkID
+			}
+
+			log.Printf("Indexing range %d-%d (network %s)\n", r.Start, r.End, rangeNetworkID)
+
+			if err := indexBlocks(rootCtx, cl, store, rangeNetworkID, r.Start, r.End, cfg.Parallelism, cfg.BatchSize, includeEvents); err != nil {
+				log.Fatal("There was an error fetching block info:", err)
+			}
+		}
+	case follow:
+		if err := runFollow(rootCtx, cl, store, networkID, startHeight, cfg.Parallelism, cfg.BatchSize, includeEvents); err != nil && rootCtx.Err() == nil {
+			log.Fatal("There was an error following new blocks:", err)
+		}
+	case endHeight < 0 || endHeight < startHeight:
+		if !resume {
+			log.Fatal("--end-height must be greater or equal to start height")
+		}
+
+		log.Println("Store is already up to date, nothing to fetch")
+	default:
+		if err := indexBlocks(rootCtx, cl, store, networkID, startHeight, endHeight, cfg.Parallelism, cfg.BatchSize, includeEvents); err != nil {
+			log.Fatal("There was an error fetching block info:", err)
+		}
+	}
+
+	if cfg.Output.Path != "" {
+		log.Println("Exporting saved blocks to a file")
+
+		if err := exportBlocks(cfg.Output.Format, cfg.Output.Path, store, networkID); err != nil {
+			log.Fatal("There was an error exporting block data:", err)
+		}
+
+		log.Println("Blocks exported to a file")
+	}
+}
+
+// indexBlocks fetches every block in [startHeight, endHeight] using a pool
+// of parallelism workers, each pulling batches of up to batchSize heights
+// off batchCh and committing the fetched blocks to store as they arrive.
+func indexBlocks(ctx context.Context, cl *client, store storage.Store, networkID string, startHeight, endHeight int64, parallelism, batchSize int, includeEvents bool) error {
+	// workerCtx is cancelled as soon as any worker hits a fatal error, so
+	// the producer below (blocked on sending to batchCh) unblocks via
+	// ctx.Done() instead of hanging forever waiting for workers that have
+	// already exited to drain it.
+	workerCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg      sync.WaitGroup
+		batchCh = make(chan []int64, parallelism)
+		errCh   = make(chan error, parallelism)
+		bar     = progressbar.Default(endHeight - startHeight + 1)
+	)
+
 	for i := 0; i < parallelism; i++ {
 		wg.Add(1)
+
 		go func() {
 			defer wg.Done()
 
-			werr := startWorker(rootCtx, cl, networkID, blockCh, resultCh)
-
-			// an error can happen only in the most critical and
-			// unrecoverable situations (e.g., with a wrong node URL)
-			if werr != nil {
-				log.Fatal("There was an error fetching block info:", err)
+			if werr := startWorker(workerCtx, cl, networkID, store, batchCh, includeEvents); werr != nil {
+				errCh <- werr
+				cancel()
 			}
 		}()
 	}
 
-	for currentHeight := startHeight; currentHeight <= endHeight; currentHeight++ {
-		blockCh <- currentHeight
+	send := func(batch []int64) bool {
+		select {
+		case batchCh <- batch:
+			return true
+		case <-workerCtx.Done():
+			return false
+		}
+	}
 
-		if err := bar.Add(1); err != nil {
-			log.Fatal("There was an error updating progress bar:", err)
+	// stop closes batchCh and waits for every worker to exit, then reports
+	// why: ctx's own cancellation (e.g., on SIGINT) takes priority, since
+	// that's also what caused workerCtx to be cancelled; otherwise it's
+	// whichever worker error caused workerCtx to be cancelled instead.
+	stop := func() error {
+		close(batchCh)
+		wg.Wait()
+		close(errCh)
+
+		if ctx.Err() != nil {
+			return ctx.Err()
 		}
+
+		// an error can happen only in the most critical and unrecoverable
+		// situations (e.g., with a wrong node URL)
+		for err := range errCh {
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
 	}
 
-	log.Println("Blocks downloaded, preparing to save them to a file")
+	batch := make([]int64, 0, batchSize)
 
-	close(blockCh)
-	wg.Wait()
-	close(resultCh)
+	for currentHeight := startHeight; currentHeight <= endHeight; currentHeight++ {
+		batch = append(batch, currentHeight)
 
-	var blocks []block
-	for b := range resultCh {
-		blocks = append(blocks, b)
+		if err := bar.Add(1); err != nil {
+			return err
+		}
+
+		if len(batch) == batchSize {
+			if !send(batch) {
+				return stop()
+			}
+
+			batch = make([]int64, 0, batchSize)
+		}
 	}
 
-	sort.Slice(blocks, func(i, j int) bool {
-		return blocks[i].Height < blocks[j].Height
-	})
+	if len(batch) > 0 && !send(batch) {
+		return stop()
+	}
 
-	if err = saveToJSONFile(outputFile, blocks); err != nil {
-		log.Fatal("There was an error saving block data to a JSON file:", err)
+	if err := stop(); err != nil {
+		return err
 	}
 
-	log.Println("Blocks saved to a file")
+	log.Println("Blocks downloaded and saved to the store")
+
+	return nil
 }
 
-// startWorker starts a new worker that listens for blocks to fetch
-// on the block channel.
-// Note that this function blocks until the block channel is closed.
-func startWorker(ctx context.Context, cl *client, networkID string, blockCh <-chan int64, resultCh chan<- block) error {
-	for height := range blockCh {
-		res, err := cl.fetchBlock(ctx, networkID, height)
-		if err != nil {
-			return err
-		}
+// startWorker starts a new worker that listens for height batches to fetch
+// on batchCh and saves each resulting block to store as it arrives. Any
+// heights the node didn't return a result for are re-sent as a smaller
+// batch, up to maxBatchItemRetries times, before the worker gives up.
+// Note that this function blocks until batchCh is closed.
+func startWorker(ctx context.Context, cl *client, networkID string, store storage.Store, batchCh <-chan []int64, includeEvents bool) error {
+	for pending := range batchCh {
+		for attempt := 0; len(pending) > 0; attempt++ {
+			if attempt >= maxBatchItemRetries {
+				return fmt.Errorf("giving up on heights %v after %d attempts", pending, maxBatchItemRetries)
+			}
 
-		resultCh <- res
+			blocks, err := cl.fetchBlocksBatch(ctx, networkID, pending, includeEvents)
+			if err != nil {
+				return err
+			}
+
+			saved := make(map[int64]bool, len(blocks))
+
+			for _, b := range blocks {
+				if err := store.SaveBlock(b.toStorageBlock()); err != nil {
+					return err
+				}
+
+				saved[b.Height] = true
+			}
+
+			retry := pending[:0]
+
+			for _, height := range pending {
+				if !saved[height] {
+					retry = append(retry, height)
+				}
+			}
+
+			pending = retry
+		}
 	}
 
 	return nil
 }
 
-// saveToJSONFile saves all blocks to a single JSON file.
-func saveToJSONFile(filename string, data []block) error {
+// exportBlocks iterates every block saved for networkID and writes them
+// to path in the given format.
+func exportBlocks(format, path string, store storage.Store, networkID string) error {
+	switch format {
+	case config.FormatJSON:
+		return exportToJSONFile(path, store, networkID)
+	case config.FormatNDJSON:
+		return exportToNDJSONFile(path, store, networkID)
+	case config.FormatBolt:
+		return exportToBoltFile(path, store, networkID)
+	default:
+		return fmt.Errorf("unknown output format %q", format)
+	}
+}
+
+// exportToJSONFile iterates every block saved for networkID and writes
+// them, in ascending height order, to a single JSON file.
+func exportToJSONFile(filename string, store storage.Store, networkID string) error {
+	var blocks []block
+
+	err := store.Iterate(networkID, func(b storage.Block) error {
+		blocks = append(blocks, blockFromStorage(b))
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
 	file, err := os.Create(filename)
 	if err != nil {
 		return err
@@ -145,5 +430,38 @@ func saveToJSONFile(filename string, data []block) error {
 	enc := json.NewEncoder(file)
 	enc.SetIndent("", "\t")
 
-	return enc.Encode(data)
+	return enc.Encode(blocks)
+}
+
+// exportToNDJSONFile iterates every block saved for networkID and writes
+// them, one JSON object per line, to filename.
+func exportToNDJSONFile(filename string, store storage.Store, networkID string) error {
+	file, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+
+	defer file.Close()
+
+	enc := json.NewEncoder(file)
+
+	return store.Iterate(networkID, func(b storage.Block) error {
+		return enc.Encode(blockFromStorage(b))
+	})
+}
+
+// exportToBoltFile iterates every block saved for networkID and copies
+// them into a standalone BoltDB file at filename, so it can be shipped
+// or archived separately from the main indexer store.
+func exportToBoltFile(filename string, store storage.Store, networkID string) error {
+	out, err := storage.NewBoltStore(filename)
+	if err != nil {
+		return err
+	}
+
+	defer out.Close()
+
+	return store.Iterate(networkID, func(b storage.Block) error {
+		return out.SaveBlock(b)
+	})
 }
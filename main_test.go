@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"cosmos-blocks/storage"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+// Test_indexBlocks_workerErrorUnblocksProducer guards against a regression
+// where a worker hitting a fatal, unrecoverable error (e.g. a batch that
+// never succeeds) would return from startWorker without unblocking the
+// producer loop, which only watches ctx.Done() when sending to batchCh.
+// With every worker dead and nothing left to drain batchCh, the producer
+// would block forever instead of reaching the errCh check that reports the
+// real failure.
+func Test_indexBlocks_workerErrorUnblocksProducer(t *testing.T) {
+	mt := httpmock.NewMockTransport()
+	mt.RegisterResponder(http.MethodPost, "http://localhost/", httpmock.NewErrorResponder(assert.AnError))
+
+	cl := &client{
+		http:       &http.Client{Transport: mt},
+		upstreams:  []*upstream{{url: "http://localhost"}},
+		maxRetries: 0,
+	}
+
+	store, err := storage.NewBoltStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("opening bolt store: %s", err)
+	}
+
+	defer store.Close()
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- indexBlocks(context.Background(), cl, store, "test1", 1, 100, 2, 1, false)
+	}()
+
+	select {
+	case err := <-done:
+		assert.Error(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("indexBlocks did not return after a worker hit a fatal error, producer is stuck")
+	}
+}
@@ -0,0 +1,107 @@
+package storage
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// BoltStore is a Store implementation backed by an embedded BoltDB
+// database file. Blocks are kept in a separate bucket per network ID,
+// keyed by their big-endian encoded height so that a bucket cursor walks
+// them in ascending order.
+type BoltStore struct {
+	db *bolt.DB
+}
+
+// NewBoltStore opens (creating if necessary) a BoltDB database file at
+// path and returns a BoltStore backed by it.
+func NewBoltStore(path string) (*BoltStore, error) {
+	db, err := bolt.Open(path, 0o600, nil)
+	if err != nil {
+		return nil, fmt.Errorf("opening bolt database: %w", err)
+	}
+
+	return &BoltStore{db: db}, nil
+}
+
+// SaveBlock implements Store.
+func (s *BoltStore) SaveBlock(b Block) error {
+	data, err := json.Marshal(b)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists(networkBucket(b.NetworkID))
+		if err != nil {
+			return err
+		}
+
+		return bucket.Put(heightKey(b.Height), data)
+	})
+}
+
+// LatestHeight implements Store.
+func (s *BoltStore) LatestHeight(networkID string) (int64, error) {
+	height := int64(-1)
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(networkBucket(networkID))
+		if bucket == nil {
+			return nil
+		}
+
+		key, _ := bucket.Cursor().Last()
+		if key == nil {
+			return nil
+		}
+
+		height = int64(binary.BigEndian.Uint64(key))
+
+		return nil
+	})
+
+	return height, err
+}
+
+// Iterate implements Store.
+func (s *BoltStore) Iterate(networkID string, fn func(Block) error) error {
+	return s.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(networkBucket(networkID))
+		if bucket == nil {
+			return nil
+		}
+
+		return bucket.ForEach(func(_, data []byte) error {
+			var b Block
+			if err := json.Unmarshal(data, &b); err != nil {
+				return err
+			}
+
+			return fn(b)
+		})
+	})
+}
+
+// Close implements Store.
+func (s *BoltStore) Close() error {
+	return s.db.Close()
+}
+
+// networkBucket returns the bucket name used to store blocks belonging to
+// the given network.
+func networkBucket(networkID string) []byte {
+	return []byte("blocks_" + networkID)
+}
+
+// heightKey encodes a block height into a big-endian, lexicographically
+// sortable bucket key.
+func heightKey(height int64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(height))
+
+	return key
+}
@@ -0,0 +1,146 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestBoltStore(t *testing.T) *BoltStore {
+	t.Helper()
+
+	s, err := NewBoltStore(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("opening bolt store: %s", err)
+	}
+
+	t.Cleanup(func() { s.Close() })
+
+	return s
+}
+
+func Test_BoltStore_LatestHeight(t *testing.T) {
+	tests := map[string]struct {
+		Blocks    []Block
+		NetworkID string
+		Result    int64
+	}{
+		"No blocks saved for the network": {
+			NetworkID: "test1",
+			Result:    -1,
+		},
+		"Returns the highest saved height": {
+			Blocks: []Block{
+				{NetworkID: "test1", Height: 5},
+				{NetworkID: "test1", Height: 10},
+				{NetworkID: "test1", Height: 7},
+			},
+			NetworkID: "test1",
+			Result:    10,
+		},
+		"Ignores blocks saved under a different network": {
+			Blocks: []Block{
+				{NetworkID: "test1", Height: 10},
+				{NetworkID: "test2", Height: 99},
+			},
+			NetworkID: "test1",
+			Result:    10,
+		},
+	}
+
+	for tname, tcase := range tests {
+		t.Run(tname, func(t *testing.T) {
+			t.Parallel()
+
+			s := newTestBoltStore(t)
+
+			for _, b := range tcase.Blocks {
+				if err := s.SaveBlock(b); err != nil {
+					t.Fatalf("saving block: %s", err)
+				}
+			}
+
+			height, err := s.LatestHeight(tcase.NetworkID)
+			assert.NoError(t, err)
+			assert.Equal(t, tcase.Result, height)
+		})
+	}
+}
+
+func Test_BoltStore_Iterate(t *testing.T) {
+	s := newTestBoltStore(t)
+
+	for _, b := range []Block{
+		{NetworkID: "test1", Height: 10, NumTxs: 2},
+		{NetworkID: "test1", Height: 5, NumTxs: 1},
+		{NetworkID: "test2", Height: 1, NumTxs: 9},
+	} {
+		if err := s.SaveBlock(b); err != nil {
+			t.Fatalf("saving block: %s", err)
+		}
+	}
+
+	var heights []int64
+
+	err := s.Iterate("test1", func(b Block) error {
+		heights = append(heights, b.Height)
+
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{5, 10}, heights)
+}
+
+func Test_BoltStore_Iterate_stopsOnError(t *testing.T) {
+	s := newTestBoltStore(t)
+
+	for _, height := range []int64{1, 2} {
+		if err := s.SaveBlock(Block{NetworkID: "test1", Height: height}); err != nil {
+			t.Fatalf("saving block: %s", err)
+		}
+	}
+
+	calls := 0
+
+	err := s.Iterate("test1", func(b Block) error {
+		calls++
+
+		return assert.AnError
+	})
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Equal(t, 1, calls)
+}
+
+func Test_BoltStore_SaveBlock_roundTrip(t *testing.T) {
+	s := newTestBoltStore(t)
+
+	want := Block{
+		NetworkID: "test1",
+		Height:    42,
+		NumTxs:    1,
+		Txs: []TxResult{
+			{Tx: []byte{0xAA}, Code: 1, GasWanted: 100, GasUsed: 90, Events: []Event{
+				{Type: "transfer", Attributes: []EventAttribute{{Key: "amount", Value: "1"}}},
+			}},
+		},
+		BeginBlockEvents: []Event{{Type: "mint"}},
+	}
+
+	if err := s.SaveBlock(want); err != nil {
+		t.Fatalf("saving block: %s", err)
+	}
+
+	var got Block
+
+	err := s.Iterate("test1", func(b Block) error {
+		got = b
+
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, want, got)
+}
@@ -0,0 +1,57 @@
+// Package storage defines a pluggable persistence layer for fetched block
+// data, so that an indexing run can be resumed after a crash or restart
+// instead of losing everything that was buffered in memory.
+package storage
+
+// Block is a persisted representation of a single chain block's metadata.
+type Block struct {
+	Height           int64      `json:"height"`
+	NumTxs           int        `json:"num_txs"`
+	NetworkID        string     `json:"network_id"`
+	Txs              []TxResult `json:"txs,omitempty"`
+	BeginBlockEvents []Event    `json:"begin_block_events,omitempty"`
+	EndBlockEvents   []Event    `json:"end_block_events,omitempty"`
+}
+
+// TxResult is a persisted representation of a single transaction within a
+// block, combining its raw bytes (from /block) with its execution outcome
+// (from /block_results).
+type TxResult struct {
+	Tx        []byte  `json:"tx"`
+	Code      uint32  `json:"code"`
+	GasWanted int64   `json:"gas_wanted"`
+	GasUsed   int64   `json:"gas_used"`
+	Events    []Event `json:"events,omitempty"`
+}
+
+// Event is a single typed event emitted during block or transaction
+// execution.
+type Event struct {
+	Type       string           `json:"type"`
+	Attributes []EventAttribute `json:"attributes,omitempty"`
+}
+
+// EventAttribute is a single key/value pair attached to an Event.
+type EventAttribute struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// Store persists block data as it is fetched and allows an interrupted
+// indexing run to be resumed from the last saved height.
+type Store interface {
+	// SaveBlock persists a single block's data.
+	SaveBlock(b Block) error
+
+	// LatestHeight returns the highest height saved for the given
+	// network, or -1 if no blocks have been saved for it yet.
+	LatestHeight(networkID string) (int64, error)
+
+	// Iterate calls fn for every block saved for the given network, in
+	// ascending height order, stopping on the first error returned by
+	// fn.
+	Iterate(networkID string, fn func(Block) error) error
+
+	// Close releases any resources held by the store.
+	Close() error
+}
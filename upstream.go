@@ -0,0 +1,278 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RPC strategies supported by the --rpc-strategy flag.
+const (
+	strategyRoundRobin  = "round-robin"
+	strategyLeastLoaded = "least-loaded"
+	strategyPriority    = "priority"
+)
+
+const (
+	maxUpstreamCooldown = time.Minute * 5
+	healthCheckInterval = time.Second * 30
+	healthCheckTimeout  = time.Second * 5
+	statsLogInterval    = time.Minute
+)
+
+var errNoHealthyUpstreams = errors.New("no healthy upstreams available")
+
+// upstream is a single RPC endpoint in the client's pool, tracked so that
+// the client can route around it once it starts failing and bring it back
+// into rotation once it recovers.
+type upstream struct {
+	url      string
+	priority int
+
+	mu                  sync.Mutex
+	inFlight            int
+	consecutiveFailures int
+	cooldownUntil       time.Time
+}
+
+// quarantined reports whether u is currently sitting out its cooldown
+// window.
+func (u *upstream) quarantined(now time.Time) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	return now.Before(u.cooldownUntil)
+}
+
+// load returns the number of requests currently in flight against u, used
+// by the least-loaded strategy.
+func (u *upstream) load() int {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	return u.inFlight
+}
+
+func (u *upstream) incInFlight() {
+	u.mu.Lock()
+	u.inFlight++
+	u.mu.Unlock()
+}
+
+func (u *upstream) decInFlight() {
+	u.mu.Lock()
+	u.inFlight--
+	u.mu.Unlock()
+}
+
+// recordSuccess clears any failure streak and cooldown, returning u to
+// full health.
+func (u *upstream) recordSuccess() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.consecutiveFailures = 0
+	u.cooldownUntil = time.Time{}
+}
+
+// recordFailure increments u's failure streak and quarantines it for a
+// window that grows with repeated failures, starting at base.
+func (u *upstream) recordFailure(now time.Time, base time.Duration) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.consecutiveFailures++
+	u.cooldownUntil = now.Add(backoffFor(u.consecutiveFailures, base))
+}
+
+// cooldown quarantines u for exactly d, regardless of its failure streak;
+// used for the 429 cooldown, which is unrelated to upstream health.
+func (u *upstream) cooldown(now time.Time, d time.Duration) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.cooldownUntil = now.Add(d)
+}
+
+// backoffFor returns the quarantine window for the given number of
+// consecutive failures, doubling base each time up to maxUpstreamCooldown.
+func backoffFor(consecutiveFailures int, base time.Duration) time.Duration {
+	d := base
+
+	for i := 1; i < consecutiveFailures && d < maxUpstreamCooldown; i++ {
+		d *= 2
+	}
+
+	if d > maxUpstreamCooldown {
+		d = maxUpstreamCooldown
+	}
+
+	return d
+}
+
+// pickUpstream selects the next upstream to use according to the client's
+// configured strategy, skipping any that are currently quarantined.
+func (c *client) pickUpstream() (*upstream, error) {
+	now := time.Now()
+
+	available := make([]*upstream, 0, len(c.upstreams))
+
+	for _, u := range c.upstreams {
+		if !u.quarantined(now) {
+			available = append(available, u)
+		}
+	}
+
+	if len(available) == 0 {
+		return nil, errNoHealthyUpstreams
+	}
+
+	switch c.strategy {
+	case strategyLeastLoaded:
+		best := available[0]
+
+		for _, u := range available[1:] {
+			if u.load() < best.load() {
+				best = u
+			}
+		}
+
+		return best, nil
+	case strategyPriority:
+		best := available[0]
+
+		for _, u := range available[1:] {
+			if u.priority < best.priority {
+				best = u
+			}
+		}
+
+		return best, nil
+	default: // strategyRoundRobin
+		if len(available) == len(c.upstreams) && len(c.rrOrder) > 0 {
+			idx := atomic.AddUint64(&c.rrCounter, 1)
+
+			return c.upstreams[c.rrOrder[idx%uint64(len(c.rrOrder))]], nil
+		}
+
+		// Some upstream is quarantined, so the precomputed weighted
+		// order may point at it; fall back to plain round-robin over
+		// what's left rather than skew towards whichever healthy
+		// upstream happens to follow it in rrOrder.
+		idx := atomic.AddUint64(&c.rrCounter, 1)
+
+		return available[idx%uint64(len(available))], nil
+	}
+}
+
+// UpstreamStats is a point-in-time health summary of a single upstream,
+// meant for logging.
+type UpstreamStats struct {
+	URL                 string
+	InFlight            int
+	ConsecutiveFailures int
+	Quarantined         bool
+}
+
+// Stats returns a health summary of every upstream in the client's pool.
+func (c *client) Stats() []UpstreamStats {
+	now := time.Now()
+	stats := make([]UpstreamStats, len(c.upstreams))
+
+	for i, u := range c.upstreams {
+		u.mu.Lock()
+		stats[i] = UpstreamStats{
+			URL:                 u.url,
+			InFlight:            u.inFlight,
+			ConsecutiveFailures: u.consecutiveFailures,
+			Quarantined:         now.Before(u.cooldownUntil),
+		}
+		u.mu.Unlock()
+	}
+
+	return stats
+}
+
+// startHealthChecks launches a background goroutine that periodically
+// polls /status on quarantined upstreams and returns them to rotation once
+// they respond successfully again. It runs until ctx is cancelled.
+func (c *client) startHealthChecks(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(healthCheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				c.checkQuarantined(ctx)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// startStatsLogging launches a background goroutine that periodically logs
+// a health summary of every upstream in the pool, so that pool behavior
+// (load balance, quarantines) is visible during a long run. It runs until
+// ctx is cancelled.
+func (c *client) startStatsLogging(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(statsLogInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				for _, s := range c.Stats() {
+					log.Printf("Upstream %s: in_flight=%d consecutive_failures=%d quarantined=%t\n",
+						s.URL, s.InFlight, s.ConsecutiveFailures, s.Quarantined)
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// checkQuarantined probes every currently quarantined upstream and clears
+// its cooldown if it responds successfully.
+func (c *client) checkQuarantined(ctx context.Context) {
+	now := time.Now()
+
+	for _, u := range c.upstreams {
+		if !u.quarantined(now) {
+			continue
+		}
+
+		u := u
+
+		reqCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+
+		req, err := http.NewRequestWithContext(reqCtx, http.MethodGet, u.url+"/status", http.NoBody)
+		if err != nil {
+			cancel()
+			continue
+		}
+
+		resp, err := c.http.Do(req)
+
+		cancel()
+
+		if err != nil {
+			continue
+		}
+
+		resp.Body.Close()
+
+		if resp.StatusCode < 500 {
+			u.recordSuccess()
+
+			log.Printf("Upstream %s is healthy again\n", u.url)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_backoffFor(t *testing.T) {
+	tests := map[string]struct {
+		ConsecutiveFailures int
+		Base                time.Duration
+		Result              time.Duration
+	}{
+		"First failure returns base": {
+			ConsecutiveFailures: 1,
+			Base:                time.Second,
+			Result:              time.Second,
+		},
+		"Doubles per additional failure": {
+			ConsecutiveFailures: 3,
+			Base:                time.Second,
+			Result:              time.Second * 4,
+		},
+		"Caps at maxUpstreamCooldown": {
+			ConsecutiveFailures: 100,
+			Base:                time.Second,
+			Result:              maxUpstreamCooldown,
+		},
+		"Base already at the cap": {
+			ConsecutiveFailures: 1,
+			Base:                maxUpstreamCooldown * 2,
+			Result:              maxUpstreamCooldown,
+		},
+	}
+
+	for tname, tcase := range tests {
+		t.Run(tname, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tcase.Result, backoffFor(tcase.ConsecutiveFailures, tcase.Base))
+		})
+	}
+}
+
+func Test_client_pickUpstream(t *testing.T) {
+	tests := map[string]struct {
+		Strategy  string
+		Upstreams []*upstream
+		RROrder   []int
+		ResultURL string
+		Error     bool
+	}{
+		"No upstreams available returns an error": {
+			Strategy: strategyRoundRobin,
+			Upstreams: []*upstream{
+				{url: "http://a", cooldownUntil: time.Now().Add(time.Minute)},
+			},
+			RROrder: []int{0},
+			Error:   true,
+		},
+		"Round-robin skips a quarantined upstream": {
+			Strategy: strategyRoundRobin,
+			Upstreams: []*upstream{
+				{url: "http://a", cooldownUntil: time.Now().Add(time.Minute)},
+				{url: "http://b"},
+			},
+			RROrder:   []int{0, 1},
+			ResultURL: "http://b",
+		},
+		"Least-loaded picks the upstream with the fewest in-flight requests": {
+			Strategy: strategyLeastLoaded,
+			Upstreams: []*upstream{
+				{url: "http://a", inFlight: 5},
+				{url: "http://b", inFlight: 1},
+				{url: "http://c", inFlight: 3},
+			},
+			ResultURL: "http://b",
+		},
+		"Priority picks the lowest priority value": {
+			Strategy: strategyPriority,
+			Upstreams: []*upstream{
+				{url: "http://a", priority: 2},
+				{url: "http://b", priority: 0},
+				{url: "http://c", priority: 1},
+			},
+			ResultURL: "http://b",
+		},
+	}
+
+	for tname, tcase := range tests {
+		t.Run(tname, func(t *testing.T) {
+			t.Parallel()
+
+			c := &client{
+				strategy:  tcase.Strategy,
+				upstreams: tcase.Upstreams,
+				rrOrder:   tcase.RROrder,
+			}
+
+			u, err := c.pickUpstream()
+			if tcase.Error {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tcase.ResultURL, u.url)
+		})
+	}
+}
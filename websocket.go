@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// newBlockEvent is the shape of a Tendermint "NewBlock" event payload, as
+// delivered over a websocket subscription.
+// More info: https://docs.cometbft.com/main/core/subscription
+type newBlockEvent struct {
+	Result struct {
+		Data struct {
+			Value struct {
+				Block struct {
+					Header struct {
+						Height string `json:"height"`
+					} `json:"header"`
+					Data struct {
+						Txs []string `json:"txs"`
+					} `json:"data"`
+				} `json:"block"`
+			} `json:"value"`
+		} `json:"data"`
+	} `json:"result"`
+}
+
+// subscribeNewBlocks dials the node's websocket endpoint and subscribes to
+// the "NewBlock" event, streaming decoded blocks into the returned channel
+// until ctx is cancelled or the connection is lost, at which point the
+// channel is closed. When includeEvents is true, each streamed block's
+// /block_results is additionally fetched and merged in, the same as
+// fetchBlock does for the back-fill path, so a --follow run's store stays
+// consistent across the back-fill/streamed boundary.
+func (c *client) subscribeNewBlocks(ctx context.Context, networkID string, includeEvents bool) (<-chan block, error) {
+	u, err := c.pickUpstream()
+	if err != nil {
+		return nil, err
+	}
+
+	wsURL := strings.Replace(u.url, "http", "ws", 1) + "/websocket"
+
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, wsURL, nil)
+	if err != nil {
+		u.recordFailure(time.Now(), c.cooldownDuration)
+
+		return nil, fmt.Errorf("dialing websocket: %w", err)
+	}
+
+	u.recordSuccess()
+
+	sub := jsonRPCRequest{
+		JSONRPC: "2.0",
+		ID:      0,
+		Method:  "subscribe",
+		Params:  map[string]string{"query": "tm.event='NewBlock'"},
+	}
+
+	if err := conn.WriteJSON(sub); err != nil {
+		conn.Close()
+
+		return nil, fmt.Errorf("sending subscribe request: %w", err)
+	}
+
+	blockCh := make(chan block)
+
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	go func() {
+		defer close(blockCh)
+		defer conn.Close()
+
+		for {
+			var ev newBlockEvent
+			if err := conn.ReadJSON(&ev); err != nil {
+				return
+			}
+
+			height, err := strconv.ParseInt(ev.Result.Data.Value.Block.Header.Height, 10, 64)
+			if err != nil {
+				// the first message received right after subscribing is
+				// just an empty acknowledgement, not a block event
+				continue
+			}
+
+			b := block{
+				Height:    height,
+				NumTxs:    len(ev.Result.Data.Value.Block.Data.Txs),
+				NetworkID: networkID,
+			}
+
+			if includeEvents {
+				br, err := c.fetchBlockResults(ctx, height)
+				if err != nil {
+					log.Printf("Failed to fetch /block_results for height %d, skipping: %s\n", height, err)
+
+					continue
+				}
+
+				txs, err := mergeTxResults(ev.Result.Data.Value.Block.Data.Txs, br.TxsResults)
+				if err != nil {
+					log.Printf("Failed to decode txs for height %d, skipping: %s\n", height, err)
+
+					continue
+				}
+
+				b.Txs = txs
+				b.BeginBlockEvents = br.BeginBlockEvents
+				b.EndBlockEvents = br.EndBlockEvents
+			}
+
+			select {
+			case blockCh <- b:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return blockCh, nil
+}